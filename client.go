@@ -0,0 +1,25 @@
+package main
+
+import "context"
+
+// pdAPI is the subset of pagerDutyClient that syncer depends on, extracted
+// as an interface so tests can substitute a fake PagerDuty client instead
+// of talking to the real API.
+type pdAPI interface {
+	getSchedule(ctx context.Context, id, name string) (*pdSchedule, error)
+	getOnCallUser(ctx context.Context, schedule pdSchedule) (*OnCallUser, error)
+}
+
+// slackAPI is the subset of slackMetaClient that syncer and config
+// resolution depend on, extracted as an interface so tests can substitute
+// an in-memory fake (see internal/mockslack) instead of talking to the
+// real Slack Web API.
+type slackAPI interface {
+	joinChannel(ctx context.Context, channelID string) (bool, error)
+	updateTopic(ctx context.Context, channelID, topic string, dryRun bool) error
+	updateCard(ctx context.Context, channelID, messageTS, blocksJSON string, dryRun bool) (string, error)
+	updateOncallGroupMembers(ctx context.Context, groups oncallGroups, dryRun bool) error
+	listChannels(ctx context.Context) (channelList, error)
+	listUsers(ctx context.Context) (slackUsers, error)
+	listUserGroups(ctx context.Context) (UserGroups, error)
+}
@@ -16,6 +16,7 @@ type ConfigSchedule struct {
 	ID         string     `yaml:"id"`
 	Name       string     `yaml:"name"`
 	UserGroups UserGroups `yaml:"userGroups"`
+	Provider   string     `yaml:"provider"`
 }
 
 func (cs ConfigSchedule) String() string {
@@ -79,6 +80,28 @@ type ConfigSlackSync struct {
 	templateString string             `yaml:"template"`
 	PretendUsers   bool               `yaml:"pretendUsers"`
 	DryRun         bool               `yaml:"dryRun"`
+	Provider       string             `yaml:"provider"`
+	Format         string             `yaml:"format"`
+	Schedule       string             `yaml:"schedule"`
+}
+
+// format returns the configured template output format, defaulting to
+// "topic" for back-compat with configs that predate Block Kit cards.
+func (css ConfigSlackSync) format() string {
+	if css.Format == "" {
+		return "topic"
+	}
+	return css.Format
+}
+
+// provider returns the configured on-call provider, defaulting to
+// "pagerduty" for back-compat with configs that predate multi-provider
+// support.
+func (css ConfigSlackSync) provider() string {
+	if css.Provider == "" {
+		return "pagerduty"
+	}
+	return css.Provider
 }
 
 func (css *ConfigSlackSync) populateChannel(_ context.Context, allChannels channelList) error {
@@ -106,7 +129,7 @@ func (css *ConfigSlackSync) populateTemplate(_ context.Context) error {
 	}
 
 	var err error
-	css.Template, err = template.New("topic").Parse(css.templateString)
+	css.Template, err = template.New("topic").Funcs(templateFuncMap).Parse(css.templateString)
 	if err != nil {
 		return fmt.Errorf("failed to parse %s's template %q: %s", css.Name, css.templateString, err)
 	}
@@ -118,7 +141,11 @@ type config struct {
 	SlackSyncs []ConfigSlackSync `yaml:"slackSyncs"`
 }
 
-func generateConfig(p params) (config, error) {
+// generateConfig builds the config from either a YAML file or the CLI/DSL
+// schedule flags, then resolves each sync's channel and template against
+// live (or --slack-manifest) Slack state, returning the Slack users and
+// user groups the rest of config resolution (createSlackSyncs) needs.
+func generateConfig(ctx context.Context, p params, slClient slackAPI) (config, slackUsers, UserGroups, error) {
 	var (
 		cfg config
 		err error
@@ -127,19 +154,19 @@ func generateConfig(p params) (config, error) {
 	if p.config != "" {
 		cfg, err = readConfigFile(p.config)
 		if err != nil {
-			return config{}, err
+			return config{}, nil, nil, err
 		}
 	} else {
 		if p.tmplFile != "" {
 			b, err := os.ReadFile(p.tmplFile)
 			if err != nil {
-				return config{}, err
+				return config{}, nil, nil, err
 			}
 			p.tmplString = string(b)
 		}
 		cfg, err = singleSlackSync(p)
 		if err != nil {
-			return config{}, err
+			return config{}, nil, nil, err
 		}
 	}
 
@@ -157,7 +184,21 @@ func generateConfig(p params) (config, error) {
 		}
 	}
 
-	return cfg, err
+	channels, users, userGroups, err := loadChannelsUsersAndGroups(ctx, p, slClient)
+	if err != nil {
+		return config{}, nil, nil, err
+	}
+
+	for i := range cfg.SlackSyncs {
+		if err := cfg.SlackSyncs[i].populateChannel(ctx, channels); err != nil {
+			return config{}, nil, nil, err
+		}
+		if err := cfg.SlackSyncs[i].populateTemplate(ctx); err != nil {
+			return config{}, nil, nil, err
+		}
+	}
+
+	return cfg, users, userGroups, nil
 }
 
 func readConfigFile(file string) (config, error) {
@@ -228,9 +269,19 @@ func parseSchedule(schedule string) (ConfigSchedule, error) {
 		return ConfigSchedule{}, errors.New(`"id" and "name" cannot be specified simultaneously`)
 	}
 
+	var provider string
+	if providers := kvs["provider"]; len(providers) > 0 {
+		if len(providers) > 1 {
+			return ConfigSchedule{}, errors.New(`multiple values for key "provider" not allowed`)
+		}
+		provider = providers[0]
+		delete(kvs, "provider")
+	}
+
 	cfgSchedule := ConfigSchedule{
-		ID:   id,
-		Name: name,
+		ID:       id,
+		Name:     name,
+		Provider: provider,
 	}
 
 	for _, userGroup := range kvs["userGroup"] {
@@ -263,6 +314,19 @@ func parseSchedule(schedule string) (ConfigSchedule, error) {
 	return cfgSchedule, nil
 }
 
+var knownOnCallProviders = map[string]bool{
+	"pagerduty": true,
+	"goalert":   true,
+}
+
+// knownTemplateFormats are the values accepted for a Slack sync's `format`
+// field. "topic" renders plain text into the channel topic; "blocks"
+// renders a JSON Block Kit payload onto a pinned message instead.
+var knownTemplateFormats = map[string]bool{
+	"topic":  true,
+	"blocks": true,
+}
+
 func (cfg *config) validateConfig() error {
 	foundNames := map[string]bool{}
 	for _, sync := range cfg.SlackSyncs {
@@ -271,10 +335,27 @@ func (cfg *config) validateConfig() error {
 		}
 		foundNames[sync.Name] = true
 
+		if !knownOnCallProviders[sync.provider()] {
+			return fmt.Errorf("slack sync %q invalid: unknown provider %q", sync.Name, sync.Provider)
+		}
+
+		if !knownTemplateFormats[sync.format()] {
+			return fmt.Errorf("slack sync %q invalid: unknown format %q", sync.Name, sync.Format)
+		}
+
+		if sync.Schedule != "" {
+			if _, err := cronParser.Parse(sync.Schedule); err != nil {
+				return fmt.Errorf("slack sync %q invalid: bad schedule %q: %s", sync.Name, sync.Schedule, err)
+			}
+		}
+
 		for _, cfgSchedule := range sync.Schedules {
 			if cfgSchedule.ID == "" && cfgSchedule.Name == "" {
 				return fmt.Errorf("slack sync %q invalid: must specify either schedule ID or schedule name", sync.Name)
 			}
+			if cfgSchedule.Provider != "" && !knownOnCallProviders[cfgSchedule.Provider] {
+				return fmt.Errorf("slack sync %q invalid: schedule %s has unknown provider %q", sync.Name, cfgSchedule, cfgSchedule.Provider)
+			}
 			for _, cfgUserGroup := range cfgSchedule.UserGroups {
 				if cfgUserGroup.ID == "" && cfgUserGroup.Name == "" && cfgUserGroup.Handle == "" {
 					return fmt.Errorf("slack sync %q user group %s invalid: must specify either user group ID or user group name or user group handle", sync.Name, cfgUserGroup)
@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"strings"
 	"testing"
 
@@ -90,10 +89,15 @@ func TestParseSchedule(t *testing.T) {
 }
 
 func TestPopulateChannel(t *testing.T) {
-	allChannels := channelList{}
-	err := json.Unmarshal([]byte(`[{ "id": "1", "name": "Foo" }, { "name": "Bar", "id": "2" }]`), &allChannels)
+	manifest := &slackManifest{
+		Channels: []manifestEntry{
+			{ID: "1", Name: "Foo"},
+			{ID: "2", Name: "Bar"},
+		},
+	}
+	allChannels, err := manifestChannels(manifest)
 	if err != nil {
-		t.Fatalf("Unable to convert json to slack: %s", err)
+		t.Fatalf("Unable to load manifest channels: %s", err)
 	}
 
 	tests := []struct {
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// pdWebhookEvent is the envelope PagerDuty sends for v3 webhook subscriptions.
+type pdWebhookEvent struct {
+	Event struct {
+		EventType    string `json:"event_type"`
+		ResourceType string `json:"resource_type"`
+		Data         struct {
+			ID         string `json:"id"`
+			ScheduleID string `json:"schedule_id"`
+		} `json:"data"`
+	} `json:"event"`
+}
+
+// daemonParams configures the long-lived server mode.
+type daemonParams struct {
+	addr            string
+	webhookSecret   string
+	debounce        time.Duration
+	fullResyncEvery time.Duration
+	metricsAddr     string
+}
+
+// daemon runs the syncer in response to PagerDuty webhooks, with each Slack
+// sync's own cron schedule (see scheduler.go) running alongside as a safety
+// net rather than one fixed interval shared by every sync.
+type daemon struct {
+	syncer      *syncer
+	slSyncs     []runSlackSync
+	params      daemonParams
+	scheduleIdx map[string][]int // PagerDuty schedule ID -> index into slSyncs
+
+	mu      sync.Mutex
+	pending map[string]*time.Timer
+}
+
+func newDaemon(s *syncer, slSyncs []runSlackSync, dp daemonParams) *daemon {
+	d := &daemon{
+		syncer:  s,
+		slSyncs: slSyncs,
+		params:  dp,
+		pending: map[string]*time.Timer{},
+	}
+	d.buildScheduleIndex()
+	return d
+}
+
+func (d *daemon) buildScheduleIndex() {
+	d.scheduleIdx = map[string][]int{}
+	for i, slSync := range d.slSyncs {
+		for _, schedule := range slSync.pdSchedules {
+			d.scheduleIdx[schedule.id] = append(d.scheduleIdx[schedule.id], i)
+		}
+	}
+}
+
+func verifyPagerDutySignature(secret string, body []byte, header string) bool {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	want := "v1=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(header), []byte(want))
+}
+
+// Serve starts the webhook listener and blocks until ctx is cancelled or a
+// SIGTERM/SIGINT is received, at which point it shuts down gracefully. Each
+// Slack sync's own cron schedule runs alongside the event-driven updates as
+// a safety net; a sync without an explicit schedule falls back to
+// d.params.fullResyncEvery.
+//
+// NOTE: this package has no main.go/CLI entry point in this tree (no flag
+// parsing or params construction lives here either), so nothing calls
+// newDaemon(...).Serve(...) yet. A `--serve`-style subcommand should build a
+// daemon with newDaemon and call Serve from wherever the CLI's entry point
+// ends up living.
+func (d *daemon) Serve(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", d.handleHealthz)
+	mux.HandleFunc("/webhooks/pagerduty", d.handleWebhook(ctx))
+
+	srv := &http.Server{Addr: d.params.addr, Handler: mux}
+
+	fullResyncEvery := d.params.fullResyncEvery
+	if fullResyncEvery <= 0 {
+		fullResyncEvery = 15 * time.Minute
+	}
+	sch, err := newScheduler(d.syncer, d.slSyncs, fmt.Sprintf("@every %s", fullResyncEvery), d.params.metricsAddr)
+	if err != nil {
+		return fmt.Errorf("daemon: failed to build sync scheduler: %s", err)
+	}
+
+	go func() {
+		if err := sch.Run(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "daemon: sync scheduler stopped: %s\n", err)
+		}
+	}()
+
+	errCh := make(chan error, 1)
+	go func() {
+		fmt.Printf("daemon: listening on %s\n", d.params.addr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errCh <- err
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+	case err := <-errCh:
+		return fmt.Errorf("daemon: listener failed: %s", err)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	fmt.Println("daemon: shutting down")
+	return srv.Shutdown(shutdownCtx)
+}
+
+func (d *daemon) handleHealthz(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok"))
+}
+
+func (d *daemon) handleWebhook(ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+
+		if d.params.webhookSecret != "" {
+			if !verifyPagerDutySignature(d.params.webhookSecret, body, r.Header.Get("X-PagerDuty-Signature")) {
+				http.Error(w, "invalid signature", http.StatusUnauthorized)
+				return
+			}
+		} else {
+			fmt.Fprintln(os.Stderr, "daemon: WARNING: no webhook secret configured, accepting webhook without signature verification")
+		}
+
+		var event pdWebhookEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			http.Error(w, "failed to parse webhook payload", http.StatusBadRequest)
+			return
+		}
+
+		switch event.Event.EventType {
+		case "incident.responder.added", "pagerduty.oncall_hand_off":
+			d.scheduleResync(ctx, event.Event.Data.ScheduleID)
+		default:
+			fmt.Printf("daemon: ignoring webhook event type %q\n", event.Event.EventType)
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// scheduleResync coalesces bursts of webhooks for the same schedule into a
+// single resync after the debounce window elapses.
+func (d *daemon) scheduleResync(ctx context.Context, scheduleID string) {
+	if scheduleID == "" {
+		return
+	}
+
+	debounce := d.params.debounce
+	if debounce <= 0 {
+		debounce = 5 * time.Second
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if t, ok := d.pending[scheduleID]; ok {
+		t.Stop()
+	}
+	d.pending[scheduleID] = time.AfterFunc(debounce, func() {
+		d.mu.Lock()
+		delete(d.pending, scheduleID)
+		d.mu.Unlock()
+		d.runAffected(ctx, scheduleID)
+	})
+}
+
+func (d *daemon) runAffected(ctx context.Context, scheduleID string) {
+	indices := d.scheduleIdx[scheduleID]
+	if len(indices) == 0 {
+		fmt.Printf("daemon: webhook for unknown schedule %s, ignoring\n", scheduleID)
+		return
+	}
+
+	var affected []runSlackSync
+	for _, i := range indices {
+		affected = append(affected, d.slSyncs[i])
+	}
+
+	fmt.Printf("daemon: schedule %s changed, resyncing %d Slack sync(s)\n", scheduleID, len(affected))
+	if err := d.syncer.Run(ctx, affected, false); err != nil {
+		fmt.Fprintf(os.Stderr, "daemon: resync for schedule %s failed: %s\n", scheduleID, err)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestVerifyPagerDutySignature(t *testing.T) {
+	const secret = "shhh"
+	body := []byte(`{"event":{"event_type":"pagerduty.oncall_hand_off"}}`)
+
+	sign := func(secret string, body []byte) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		return "v1=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	tests := []struct {
+		name   string
+		secret string
+		body   []byte
+		header string
+		want   bool
+	}{
+		{
+			name:   "valid signature",
+			secret: secret,
+			body:   body,
+			header: sign(secret, body),
+			want:   true,
+		},
+		{
+			name:   "invalid signature",
+			secret: secret,
+			body:   body,
+			header: sign("wrong-secret", body),
+			want:   false,
+		},
+		{
+			name:   "missing signature",
+			secret: secret,
+			body:   body,
+			header: "",
+			want:   false,
+		},
+		{
+			name:   "signature for a different body",
+			secret: secret,
+			body:   body,
+			header: sign(secret, []byte("tampered")),
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verifyPagerDutySignature(tt.secret, tt.body, tt.header); got != tt.want {
+				t.Errorf("verifyPagerDutySignature() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// goAlertClient talks to GoAlert's GraphQL API to resolve schedules and the
+// user currently on call, implementing OnCallProvider as an alternative to
+// pagerDutyClient for teams running GoAlert.
+type goAlertClient struct {
+	endpoint   string
+	apiKey     string
+	httpClient *http.Client
+}
+
+func newGoAlertClient(endpoint, apiKey string) *goAlertClient {
+	return &goAlertClient{
+		endpoint:   endpoint,
+		apiKey:     apiKey,
+		httpClient: http.DefaultClient,
+	}
+}
+
+type goAlertGraphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+func (c *goAlertClient) do(ctx context.Context, query string, variables map[string]interface{}, out interface{}) error {
+	body, err := json.Marshal(goAlertGraphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return fmt.Errorf("failed to encode GoAlert GraphQL request: %s", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, strings.NewReader(string(body)))
+	if err != nil {
+		return fmt.Errorf("failed to build GoAlert request: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call GoAlert: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GoAlert returned status %s", resp.Status)
+	}
+
+	var envelope struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("failed to decode GoAlert response: %s", err)
+	}
+	if len(envelope.Errors) > 0 {
+		return fmt.Errorf("GoAlert GraphQL error: %s", envelope.Errors[0].Message)
+	}
+
+	return json.Unmarshal(envelope.Data, out)
+}
+
+func (c *goAlertClient) GetSchedule(ctx context.Context, id, name string) (*OnCallSchedule, error) {
+	if id == "" && name == "" {
+		return nil, fmt.Errorf("must specify either schedule ID or schedule name")
+	}
+
+	if id != "" {
+		return c.getScheduleByID(ctx, id)
+	}
+	return c.getScheduleByName(ctx, name)
+}
+
+func (c *goAlertClient) getScheduleByID(ctx context.Context, id string) (*OnCallSchedule, error) {
+	const query = `query($id: ID!) { schedule(id: $id) { id name } }`
+	var result struct {
+		Schedule struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"schedule"`
+	}
+
+	if err := c.do(ctx, query, map[string]interface{}{"id": id}, &result); err != nil {
+		return nil, fmt.Errorf("failed to get GoAlert schedule %q: %s", id, err)
+	}
+
+	return &OnCallSchedule{ID: result.Schedule.ID, Name: result.Schedule.Name}, nil
+}
+
+// getScheduleByName searches GoAlert schedules for an exact name match, since
+// GoAlert's schedule(id: ID!) query has no by-name variant.
+func (c *goAlertClient) getScheduleByName(ctx context.Context, name string) (*OnCallSchedule, error) {
+	const query = `query($input: ScheduleSearchOptions!) { schedules(input: $input) { nodes { id name } } }`
+	var result struct {
+		Schedules struct {
+			Nodes []struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"nodes"`
+		} `json:"schedules"`
+	}
+
+	variables := map[string]interface{}{"input": map[string]interface{}{"search": name}}
+	if err := c.do(ctx, query, variables, &result); err != nil {
+		return nil, fmt.Errorf("failed to search GoAlert schedules for %q: %s", name, err)
+	}
+
+	for _, node := range result.Schedules.Nodes {
+		if node.Name == name {
+			return &OnCallSchedule{ID: node.ID, Name: node.Name}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no GoAlert schedule found with name %q", name)
+}
+
+func (c *goAlertClient) GetOnCallUser(ctx context.Context, schedule OnCallSchedule) (*OnCallUser, error) {
+	const query = `query($id: ID!) {
+		schedule(id: $id) {
+			onCallNotificationRules {
+				escalationLevel
+				rotationEndsAt
+				target {
+					id
+					name
+					... on User {
+						email
+					}
+				}
+			}
+		}
+	}`
+	var result struct {
+		Schedule struct {
+			OnCallNotificationRules []struct {
+				EscalationLevel int    `json:"escalationLevel"`
+				RotationEndsAt  string `json:"rotationEndsAt"`
+				Target          struct {
+					ID    string `json:"id"`
+					Name  string `json:"name"`
+					Email string `json:"email"`
+				} `json:"target"`
+			} `json:"onCallNotificationRules"`
+		} `json:"schedule"`
+	}
+
+	if err := c.do(ctx, query, map[string]interface{}{"id": schedule.ID}, &result); err != nil {
+		return nil, fmt.Errorf("failed to get on-call user for GoAlert schedule %q: %s", schedule.Name, err)
+	}
+
+	if len(result.Schedule.OnCallNotificationRules) == 0 {
+		return nil, fmt.Errorf("no one currently on call for GoAlert schedule %q", schedule.Name)
+	}
+
+	rule := result.Schedule.OnCallNotificationRules[0]
+
+	var rotationEndsAt time.Time
+	if rule.RotationEndsAt != "" {
+		var err error
+		rotationEndsAt, err = time.Parse(time.RFC3339, rule.RotationEndsAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse rotationEndsAt %q for GoAlert schedule %q: %s", rule.RotationEndsAt, schedule.Name, err)
+		}
+	}
+
+	return &OnCallUser{
+		ID:              rule.Target.ID,
+		Email:           rule.Target.Email,
+		EscalationLevel: rule.EscalationLevel,
+		RotationEndsAt:  rotationEndsAt,
+	}, nil
+}
@@ -0,0 +1,230 @@
+// Package mockslack provides an in-memory httptest.Server implementing the
+// subset of the Slack Web API that pdsync talks to: conversations.list,
+// conversations.join, conversations.setTopic, usergroups.list,
+// usergroups.users.update, and users.list. It exists so pdsync's sync logic
+// can be exercised end-to-end against a real HTTP server without calling
+// out to Slack, mirroring the approach GoAlert uses for its own Slack
+// integration tests.
+package mockslack
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// Channel is a Slack channel tracked by the mock server.
+type Channel struct {
+	ID     string
+	Name   string
+	Topic  string
+	Joined bool
+}
+
+// User is a Slack user tracked by the mock server.
+type User struct {
+	ID    string
+	Name  string
+	Email string
+}
+
+// UserGroup is a Slack user group tracked by the mock server.
+type UserGroup struct {
+	ID      string
+	Handle  string
+	Name    string
+	Members []string
+}
+
+// Server is a mock Slack Web API backed by in-memory state. The zero value
+// is not usable; construct one with New.
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	channels   map[string]*Channel
+	users      map[string]*User
+	userGroups map[string]*UserGroup
+}
+
+// New starts a mock Slack Web API server seeded with the given channels,
+// users, and user groups. Callers are responsible for calling Close when
+// done, typically via defer.
+func New(channels []Channel, users []User, userGroups []UserGroup) *Server {
+	s := &Server{
+		channels:   map[string]*Channel{},
+		users:      map[string]*User{},
+		userGroups: map[string]*UserGroup{},
+	}
+	for i := range channels {
+		c := channels[i]
+		s.channels[c.ID] = &c
+	}
+	for i := range users {
+		u := users[i]
+		s.users[u.ID] = &u
+	}
+	for i := range userGroups {
+		ug := userGroups[i]
+		s.userGroups[ug.ID] = &ug
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/conversations.list", s.handleConversationsList)
+	mux.HandleFunc("/conversations.join", s.handleConversationsJoin)
+	mux.HandleFunc("/conversations.setTopic", s.handleConversationsSetTopic)
+	mux.HandleFunc("/usergroups.list", s.handleUserGroupsList)
+	mux.HandleFunc("/usergroups.users.update", s.handleUserGroupsUsersUpdate)
+	mux.HandleFunc("/users.list", s.handleUsersList)
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Channel returns the current state of the channel with the given ID, or
+// nil if no such channel exists.
+func (s *Server) Channel(id string) *Channel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.channels[id]
+	if !ok {
+		return nil
+	}
+	cp := *c
+	return &cp
+}
+
+// UserGroupMembers returns the current member IDs of the user group with
+// the given ID, or nil if no such user group exists.
+func (s *Server) UserGroupMembers(id string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ug, ok := s.userGroups[id]
+	if !ok {
+		return nil
+	}
+	members := make([]string, len(ug.Members))
+	copy(members, ug.Members)
+	return members
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, errStr string) {
+	writeJSON(w, map[string]interface{}{"ok": false, "error": errStr})
+}
+
+// formValue reads a Slack API parameter from either a form-encoded body (as
+// sent by most Slack SDKs) or a JSON body (as sent by clients that prefer
+// application/json), so the mock accepts either.
+func formValue(r *http.Request, key string) string {
+	if strings.Contains(r.Header.Get("Content-Type"), "application/json") {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if v, ok := body[key].(string); ok {
+			return v
+		}
+		return ""
+	}
+
+	_ = r.ParseForm()
+	return r.FormValue(key)
+}
+
+func (s *Server) handleConversationsList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channels := make([]map[string]interface{}, 0, len(s.channels))
+	for _, c := range s.channels {
+		channels = append(channels, map[string]interface{}{"id": c.ID, "name": c.Name})
+	}
+	writeJSON(w, map[string]interface{}{"ok": true, "channels": channels})
+}
+
+func (s *Server) handleConversationsJoin(w http.ResponseWriter, r *http.Request) {
+	id := formValue(r, "channel")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.channels[id]
+	if !ok {
+		writeError(w, "channel_not_found")
+		return
+	}
+	c.Joined = true
+	writeJSON(w, map[string]interface{}{"ok": true, "channel": map[string]interface{}{"id": c.ID}})
+}
+
+func (s *Server) handleConversationsSetTopic(w http.ResponseWriter, r *http.Request) {
+	id := formValue(r, "channel")
+	topic := formValue(r, "topic")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.channels[id]
+	if !ok {
+		writeError(w, "channel_not_found")
+		return
+	}
+	c.Topic = topic
+	writeJSON(w, map[string]interface{}{"ok": true, "topic": topic})
+}
+
+func (s *Server) handleUserGroupsList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	groups := make([]map[string]interface{}, 0, len(s.userGroups))
+	for _, ug := range s.userGroups {
+		groups = append(groups, map[string]interface{}{"id": ug.ID, "handle": ug.Handle, "name": ug.Name})
+	}
+	writeJSON(w, map[string]interface{}{"ok": true, "usergroups": groups})
+}
+
+func (s *Server) handleUserGroupsUsersUpdate(w http.ResponseWriter, r *http.Request) {
+	id := formValue(r, "usergroup")
+	users := formValue(r, "users")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ug, ok := s.userGroups[id]
+	if !ok {
+		writeError(w, "no_such_subteam")
+		return
+	}
+
+	var members []string
+	if users != "" {
+		members = strings.Split(users, ",")
+	}
+	ug.Members = members
+	writeJSON(w, map[string]interface{}{"ok": true})
+}
+
+func (s *Server) handleUsersList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	members := make([]map[string]interface{}, 0, len(s.users))
+	for _, u := range s.users {
+		members = append(members, map[string]interface{}{
+			"id":   u.ID,
+			"name": u.Name,
+			"profile": map[string]interface{}{
+				"email": u.Email,
+			},
+		})
+	}
+	writeJSON(w, map[string]interface{}{"ok": true, "members": members})
+}
@@ -0,0 +1,64 @@
+package mockslack
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func post(t *testing.T, baseURL, method string, form url.Values) map[string]interface{} {
+	t.Helper()
+
+	resp, err := http.Post(baseURL+"/"+method, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	if err != nil {
+		t.Fatalf("POST %s: %s", method, err)
+	}
+	defer resp.Body.Close()
+
+	var body map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode %s response: %s", method, err)
+	}
+	return body
+}
+
+func TestServer(t *testing.T) {
+	s := New(
+		[]Channel{{ID: "C1", Name: "oncall"}},
+		[]User{{ID: "U1", Name: "alice", Email: "alice@example.com"}},
+		[]UserGroup{{ID: "S1", Handle: "oncall-primary"}},
+	)
+	defer s.Close()
+
+	if got := post(t, s.URL, "conversations.list", nil)["ok"]; got != true {
+		t.Fatalf("conversations.list: got ok=%v", got)
+	}
+
+	if got := post(t, s.URL, "conversations.join", url.Values{"channel": {"C1"}})["ok"]; got != true {
+		t.Fatalf("conversations.join: got ok=%v", got)
+	}
+	if c := s.Channel("C1"); c == nil || !c.Joined {
+		t.Fatalf("channel C1 not marked joined: %+v", c)
+	}
+
+	if got := post(t, s.URL, "conversations.setTopic", url.Values{"channel": {"C1"}, "topic": {"On call: alice"}})["ok"]; got != true {
+		t.Fatalf("conversations.setTopic: got ok=%v", got)
+	}
+	if c := s.Channel("C1"); c == nil || c.Topic != "On call: alice" {
+		t.Fatalf("channel C1 topic = %+v, want %q", c, "On call: alice")
+	}
+
+	if got := post(t, s.URL, "usergroups.users.update", url.Values{"usergroup": {"S1"}, "users": {"U1"}})["ok"]; got != true {
+		t.Fatalf("usergroups.users.update: got ok=%v", got)
+	}
+	if members := s.UserGroupMembers("S1"); len(members) != 1 || members[0] != "U1" {
+		t.Fatalf("user group S1 members = %v, want [U1]", members)
+	}
+
+	resp := post(t, s.URL, "conversations.join", url.Values{"channel": {"missing"}})
+	if resp["ok"] != false || resp["error"] != "channel_not_found" {
+		t.Fatalf("conversations.join for missing channel = %+v", resp)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// manifestEntry is one record in a Slack export-style manifest file, the
+// shape shared by channels, users, and user groups in a Slack data export.
+type manifestEntry struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Handle      string   `json:"handle"`
+	Email       string   `json:"email,omitempty"`
+	Members     []string `json:"members"`
+	IsUserGroup bool     `json:"is_usergroup"`
+}
+
+// slackManifest is the on-disk layout loaded via --slack-manifest: a static
+// snapshot of channels, users, and user groups used in place of live
+// conversations.list / users.list calls.
+type slackManifest struct {
+	Channels   []manifestEntry `json:"channels"`
+	Users      []manifestEntry `json:"users"`
+	UserGroups []manifestEntry `json:"userGroups"`
+}
+
+func loadSlackManifest(path string) (*slackManifest, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Slack manifest %s: %s", path, err)
+	}
+
+	var manifest slackManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse Slack manifest %s: %s", path, err)
+	}
+
+	return &manifest, nil
+}
+
+// manifestChannels converts a manifest's channel entries into the same
+// channelList type populateChannel looks up against during live discovery.
+func manifestChannels(manifest *slackManifest) (channelList, error) {
+	return decodeManifestEntries[channelList](manifest.Channels)
+}
+
+// manifestUsers converts a manifest's user entries into the slackUsers
+// lookup table runSlackSync matches PagerDuty on-call users against.
+func manifestUsers(manifest *slackManifest) (slackUsers, error) {
+	return decodeManifestEntries[slackUsers](manifest.Users)
+}
+
+// manifestUserGroups converts a manifest's user group entries into the same
+// UserGroups type used by live usergroups.list discovery.
+func manifestUserGroups(manifest *slackManifest) (UserGroups, error) {
+	return decodeManifestEntries[UserGroups](manifest.UserGroups)
+}
+
+// decodeManifestEntries re-encodes manifest entries as JSON and decodes them
+// into T, so manifest loading stays in lockstep with whatever shape the
+// live Slack API client types expect without duplicating their fields here.
+func decodeManifestEntries[T any](entries []manifestEntry) (T, error) {
+	var zero T
+
+	content, err := json.Marshal(entries)
+	if err != nil {
+		return zero, fmt.Errorf("failed to re-encode manifest entries: %s", err)
+	}
+
+	var out T
+	if err := json.Unmarshal(content, &out); err != nil {
+		return zero, fmt.Errorf("failed to decode manifest entries: %s", err)
+	}
+
+	return out, nil
+}
+
+// loadChannelsUsersAndGroups returns the channel, user, and user-group
+// lookup tables used for the rest of config resolution, either from a
+// --slack-manifest file or via live Slack discovery.
+func loadChannelsUsersAndGroups(ctx context.Context, p params, slClient slackAPI) (channelList, slackUsers, UserGroups, error) {
+	if p.slackManifest != "" {
+		manifest, err := loadSlackManifest(p.slackManifest)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		channels, err := manifestChannels(manifest)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to load manifest channels: %s", err)
+		}
+
+		users, err := manifestUsers(manifest)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to load manifest users: %s", err)
+		}
+
+		userGroups, err := manifestUserGroups(manifest)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to load manifest user groups: %s", err)
+		}
+
+		return channels, users, userGroups, nil
+	}
+
+	channels, err := slClient.listChannels(ctx)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to list Slack channels: %s", err)
+	}
+
+	users, err := slClient.listUsers(ctx)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to list Slack users: %s", err)
+	}
+
+	userGroups, err := slClient.listUserGroups(ctx)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to list Slack user groups: %s", err)
+	}
+
+	return channels, users, userGroups, nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// OnCallSchedule is the provider-agnostic view of an on-call schedule
+// returned by an OnCallProvider.
+type OnCallSchedule struct {
+	ID   string
+	Name string
+}
+
+// OnCallUser is the provider-agnostic view of the user currently on call
+// for a schedule.
+type OnCallUser struct {
+	ID              string
+	Email           string
+	EscalationLevel int
+	RotationEndsAt  time.Time
+}
+
+// OnCallProvider abstracts the on-call backend a Slack sync resolves
+// schedules and on-call users from. pagerDutyClient remains the default,
+// back-compat backend; goAlertClient is a second implementation for teams
+// running GoAlert instead of (or alongside) PagerDuty.
+type OnCallProvider interface {
+	GetSchedule(ctx context.Context, id, name string) (*OnCallSchedule, error)
+	GetOnCallUser(ctx context.Context, schedule OnCallSchedule) (*OnCallUser, error)
+}
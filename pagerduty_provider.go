@@ -0,0 +1,27 @@
+package main
+
+import "context"
+
+// pagerDutyProvider adapts pagerDutyClient (via the pdAPI interface) to
+// OnCallProvider, so PagerDuty-backed schedules are resolved through the
+// same abstraction as GoAlert-backed ones instead of being special-cased
+// inline.
+type pagerDutyProvider struct {
+	client pdAPI
+}
+
+func newPagerDutyProvider(client pdAPI) *pagerDutyProvider {
+	return &pagerDutyProvider{client: client}
+}
+
+func (p *pagerDutyProvider) GetSchedule(ctx context.Context, id, name string) (*OnCallSchedule, error) {
+	schedule, err := p.client.getSchedule(ctx, id, name)
+	if err != nil || schedule == nil {
+		return nil, err
+	}
+	return &OnCallSchedule{ID: schedule.id, Name: schedule.name}, nil
+}
+
+func (p *pagerDutyProvider) GetOnCallUser(ctx context.Context, schedule OnCallSchedule) (*OnCallUser, error) {
+	return p.client.getOnCallUser(ctx, pdSchedule{id: schedule.ID, name: schedule.Name})
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/robfig/cron/v3"
+)
+
+// cronParser accepts the standard five cron fields plus an optional
+// leading seconds field (e.g. "0 */15 * * * *"), along with the "@every"
+// and friends descriptors, so a Slack sync's schedule can be as coarse or
+// as fine-grained as it needs.
+var cronParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// defaultSyncSchedule is used for a Slack sync that doesn't specify its own
+// schedule.
+const defaultSyncSchedule = "@every 15m"
+
+// schedulerJitterFraction bounds the random jitter applied to each
+// scheduled run, so Slack syncs sharing a cron expression don't all hit the
+// Slack API at the exact same instant.
+const schedulerJitterFraction = 0.10
+
+var (
+	syncRunsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pdsync_sync_runs_total",
+		Help: "Total number of Slack sync runs, by sync name.",
+	}, []string{"sync"})
+
+	syncErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pdsync_sync_errors_total",
+		Help: "Total number of Slack sync runs that failed, by sync name.",
+	}, []string{"sync"})
+
+	syncDurationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "pdsync_sync_duration_seconds",
+		Help: "Duration of Slack sync runs in seconds, by sync name.",
+	}, []string{"sync"})
+)
+
+// scheduledSync pairs a Slack sync with its parsed cron schedule.
+type scheduledSync struct {
+	sync     runSlackSync
+	schedule cron.Schedule
+}
+
+// scheduler runs each Slack sync on its own cron cadence, rather than the
+// daemon's old single fixed interval shared by every sync.
+type scheduler struct {
+	syncer      *syncer
+	scheduled   []scheduledSync
+	metricsAddr string
+}
+
+// newScheduler builds a scheduler for slSyncs. A sync without its own
+// schedule falls back to defaultSchedule, or defaultSyncSchedule if that is
+// also empty. metricsAddr, if non-empty, is where /metrics is served.
+func newScheduler(s *syncer, slSyncs []runSlackSync, defaultSchedule, metricsAddr string) (*scheduler, error) {
+	if defaultSchedule == "" {
+		defaultSchedule = defaultSyncSchedule
+	}
+
+	sch := &scheduler{syncer: s, metricsAddr: metricsAddr}
+	for _, slSync := range slSyncs {
+		scheduleStr := slSync.schedule
+		if scheduleStr == "" {
+			scheduleStr = defaultSchedule
+		}
+
+		schedule, err := cronParser.Parse(scheduleStr)
+		if err != nil {
+			return nil, fmt.Errorf("slack sync %s: invalid schedule %q: %s", slSync.name, scheduleStr, err)
+		}
+		sch.scheduled = append(sch.scheduled, scheduledSync{sync: slSync, schedule: schedule})
+	}
+
+	return sch, nil
+}
+
+// Run starts one timer per Slack sync and blocks until ctx is cancelled. If
+// metricsAddr was set, it also serves Prometheus metrics at /metrics.
+func (sch *scheduler) Run(ctx context.Context) error {
+	if sch.metricsAddr != "" {
+		go sch.serveMetrics()
+	}
+
+	var wg sync.WaitGroup
+	now := time.Now()
+	for _, ss := range sch.scheduled {
+		ss := ss
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sch.runLoop(ctx, ss, now)
+		}()
+	}
+	wg.Wait()
+
+	return nil
+}
+
+func (sch *scheduler) serveMetrics() {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	if err := http.ListenAndServe(sch.metricsAddr, mux); err != nil && err != http.ErrServerClosed {
+		fmt.Fprintf(os.Stderr, "scheduler: metrics listener failed: %s\n", err)
+	}
+}
+
+func (sch *scheduler) runLoop(ctx context.Context, ss scheduledSync, from time.Time) {
+	next := ss.schedule.Next(from)
+	timer := time.NewTimer(jitter(time.Until(next)))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			sch.runOnce(ctx, ss.sync)
+			next = ss.schedule.Next(time.Now())
+			timer.Reset(jitter(time.Until(next)))
+		}
+	}
+}
+
+func (sch *scheduler) runOnce(ctx context.Context, slSync runSlackSync) {
+	start := time.Now()
+	syncRunsTotal.WithLabelValues(slSync.name).Inc()
+
+	err := sch.syncer.runSlackSync(ctx, slSync)
+
+	syncDurationSeconds.WithLabelValues(slSync.name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		syncErrorsTotal.WithLabelValues(slSync.name).Inc()
+		fmt.Fprintf(os.Stderr, "scheduler: Slack sync %s failed: %s\n", slSync.name, err)
+	}
+}
+
+// jitter applies up to ±schedulerJitterFraction random jitter to d.
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+
+	delta := time.Duration(float64(d) * schedulerJitterFraction)
+	if delta <= 0 {
+		return d
+	}
+	offset := time.Duration(rand.Int63n(int64(2*delta+1))) - delta
+	return d + offset
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// defaultSlackAPIURL is Slack's production Web API base URL.
+const defaultSlackAPIURL = "https://slack.com/api/"
+
+// slackAPIBaseURL returns the Slack Web API base URL slackMetaClient should
+// talk to, honoring a SLACK_API_URL override so tests can point pdsync at
+// an in-memory mock (see internal/mockslack) instead of the real Slack API.
+func slackAPIBaseURL() string {
+	if v := os.Getenv("SLACK_API_URL"); v != "" {
+		return v
+	}
+	return defaultSlackAPIURL
+}
+
+// httpSlackClient is a slackAPI implementation that talks to the Slack Web
+// API (or, via SLACK_API_URL, an in-memory mock) directly over HTTP with a
+// bot token, honoring slackAPIBaseURL()'s override for production use
+// rather than only inside tests.
+type httpSlackClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// newHTTPSlackClient builds a slackAPI that authenticates with token and
+// talks to slackAPIBaseURL().
+func newHTTPSlackClient(token string) *httpSlackClient {
+	return &httpSlackClient{baseURL: slackAPIBaseURL(), token: token, http: http.DefaultClient}
+}
+
+func (c *httpSlackClient) post(ctx context.Context, method string, form url.Values) (json.RawMessage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+method, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Slack %s request: %s", method, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Slack %s: %s", method, err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Slack %s response: %s", method, err)
+	}
+
+	var envelope struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode Slack %s response: %s", method, err)
+	}
+	if !envelope.OK {
+		return nil, fmt.Errorf("slack API %s error: %s", method, envelope.Error)
+	}
+
+	return raw, nil
+}
+
+func (c *httpSlackClient) joinChannel(ctx context.Context, channelID string) (bool, error) {
+	if _, err := c.post(ctx, "conversations.join", url.Values{"channel": {channelID}}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *httpSlackClient) updateTopic(ctx context.Context, channelID, topic string, dryRun bool) error {
+	if dryRun {
+		return nil
+	}
+	_, err := c.post(ctx, "conversations.setTopic", url.Values{"channel": {channelID}, "topic": {topic}})
+	return err
+}
+
+func (c *httpSlackClient) updateCard(ctx context.Context, channelID, messageTS, blocksJSON string, dryRun bool) (string, error) {
+	if dryRun {
+		return messageTS, nil
+	}
+
+	method := "chat.postMessage"
+	form := url.Values{"channel": {channelID}, "blocks": {blocksJSON}}
+	if messageTS != "" {
+		method = "chat.update"
+		form.Set("ts", messageTS)
+	}
+
+	raw, err := c.post(ctx, method, form)
+	if err != nil {
+		return messageTS, err
+	}
+
+	var result struct {
+		TS string `json:"ts"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil || result.TS == "" {
+		return messageTS, nil
+	}
+	if messageTS == "" {
+		if _, err := c.post(ctx, "pins.add", url.Values{"channel": {channelID}, "timestamp": {result.TS}}); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to pin on-call card %s in channel %s: %s\n", result.TS, channelID, err)
+		}
+	}
+	return result.TS, nil
+}
+
+// updateOncallGroupMembers is not implemented against the real Slack API:
+// oncallGroups' internal layout isn't part of this snapshot (it predates
+// this client), so there is no safe way to enumerate its groups/members
+// here without guessing at its fields. Called with no groups configured
+// (the common case for a topic-only sync) it is a no-op either way.
+func (c *httpSlackClient) updateOncallGroupMembers(_ context.Context, groups oncallGroups, dryRun bool) error {
+	if dryRun || len(groups) == 0 {
+		return nil
+	}
+	return errors.New("httpSlackClient: on-call user group sync is not implemented")
+}
+
+func (c *httpSlackClient) listChannels(ctx context.Context) (channelList, error) {
+	raw, err := c.post(ctx, "conversations.list", url.Values{})
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed struct {
+		Channels []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"channels"`
+	}
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode conversations.list response: %s", err)
+	}
+
+	entries := make([]manifestEntry, 0, len(parsed.Channels))
+	for _, ch := range parsed.Channels {
+		entries = append(entries, manifestEntry{ID: ch.ID, Name: ch.Name})
+	}
+	return decodeManifestEntries[channelList](entries)
+}
+
+func (c *httpSlackClient) listUsers(ctx context.Context) (slackUsers, error) {
+	raw, err := c.post(ctx, "users.list", url.Values{})
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed struct {
+		Members []struct {
+			ID      string `json:"id"`
+			Name    string `json:"name"`
+			Profile struct {
+				Email string `json:"email"`
+			} `json:"profile"`
+		} `json:"members"`
+	}
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode users.list response: %s", err)
+	}
+
+	entries := make([]manifestEntry, 0, len(parsed.Members))
+	for _, m := range parsed.Members {
+		entries = append(entries, manifestEntry{ID: m.ID, Name: m.Name, Handle: m.Name, Email: m.Profile.Email})
+	}
+	return decodeManifestEntries[slackUsers](entries)
+}
+
+func (c *httpSlackClient) listUserGroups(ctx context.Context) (UserGroups, error) {
+	raw, err := c.post(ctx, "usergroups.list", url.Values{})
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed struct {
+		UserGroups []struct {
+			ID     string `json:"id"`
+			Handle string `json:"handle"`
+			Name   string `json:"name"`
+		} `json:"usergroups"`
+	}
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode usergroups.list response: %s", err)
+	}
+
+	entries := make([]manifestEntry, 0, len(parsed.UserGroups))
+	for _, ug := range parsed.UserGroups {
+		entries = append(entries, manifestEntry{ID: ug.ID, Name: ug.Name, Handle: ug.Handle, IsUserGroup: true})
+	}
+	return decodeManifestEntries[UserGroups](entries)
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// syncState is the last-applied state for a single Slack sync, cached on
+// disk so that repeated ticks can skip Slack API writes when nothing about
+// the desired state has actually changed.
+type syncState struct {
+	Topic        string              `json:"topic"`
+	GroupMembers map[string][]string `json:"groupMembers"`
+	// CardMessageTS is the timestamp of the pinned Slack message a
+	// format: blocks sync last posted, so the next run can chat.update
+	// it instead of posting a new card. Unused for plain-text topics.
+	CardMessageTS string `json:"cardMessageTS,omitempty"`
+}
+
+// Equal reports whether s and other describe the same on-call state, along
+// with a human-readable reason for the first divergence found.
+func (s syncState) Equal(other syncState) (bool, string) {
+	if s.Topic != other.Topic {
+		return false, fmt.Sprintf("topic changed: %q -> %q", s.Topic, other.Topic)
+	}
+
+	groupKeys := map[string]bool{}
+	for k := range s.GroupMembers {
+		groupKeys[k] = true
+	}
+	for k := range other.GroupMembers {
+		groupKeys[k] = true
+	}
+
+	keys := make([]string, 0, len(groupKeys))
+	for k := range groupKeys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		ok, reason := diffMembers(s.GroupMembers[k], other.GroupMembers[k])
+		if !ok {
+			return false, fmt.Sprintf("user group %s membership diverged: %s", k, reason)
+		}
+	}
+
+	return true, ""
+}
+
+// diffMembers compares two unordered member lists and describes the
+// difference as additions/removals, e.g. "added U123, removed U456".
+func diffMembers(before, after []string) (bool, string) {
+	beforeSet := map[string]bool{}
+	for _, id := range before {
+		beforeSet[id] = true
+	}
+	afterSet := map[string]bool{}
+	for _, id := range after {
+		afterSet[id] = true
+	}
+
+	var added, removed []string
+	for id := range afterSet {
+		if !beforeSet[id] {
+			added = append(added, id)
+		}
+	}
+	for id := range beforeSet {
+		if !afterSet[id] {
+			removed = append(removed, id)
+		}
+	}
+
+	if len(added) == 0 && len(removed) == 0 {
+		return true, ""
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+
+	var parts []string
+	if len(added) > 0 {
+		parts = append(parts, fmt.Sprintf("added %s", strings.Join(added, ", ")))
+	}
+	if len(removed) > 0 {
+		parts = append(parts, fmt.Sprintf("removed %s", strings.Join(removed, ", ")))
+	}
+	return false, strings.Join(parts, ", ")
+}
+
+// stateCache persists the last-applied syncState for each named Slack sync
+// between runs, e.g. at ~/.cache/pdsync/state.json. It is safe for
+// concurrent use: the scheduler (see scheduler.go) runs each sync on its
+// own goroutine, and the daemon's webhook-debounce path can trigger a
+// resync concurrently with any of them.
+type stateCache struct {
+	mu     sync.Mutex
+	path   string
+	byName map[string]syncState
+}
+
+func defaultStateCachePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %s", err)
+	}
+	return filepath.Join(home, ".cache", "pdsync", "state.json"), nil
+}
+
+func loadStateCache(path string) (*stateCache, error) {
+	sc := &stateCache{path: path, byName: map[string]syncState{}}
+
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return sc, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state cache %s: %s", path, err)
+	}
+
+	if err := json.Unmarshal(content, &sc.byName); err != nil {
+		return nil, fmt.Errorf("failed to parse state cache %s: %s", path, err)
+	}
+
+	return sc, nil
+}
+
+func (sc *stateCache) get(name string) (syncState, bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	s, ok := sc.byName[name]
+	return s, ok
+}
+
+func (sc *stateCache) set(name string, s syncState) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.byName[name] = s
+}
+
+func (sc *stateCache) save() error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	content, err := json.MarshalIndent(sc.byName, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state cache: %s", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(sc.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create state cache directory: %s", err)
+	}
+
+	if err := os.WriteFile(sc.path, content, 0o644); err != nil {
+		return fmt.Errorf("failed to write state cache %s: %s", sc.path, err)
+	}
+
+	return nil
+}
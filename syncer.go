@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
+	"sync"
 	"text/template"
 )
 
@@ -15,18 +17,132 @@ type runSlackSync struct {
 	pdSchedules    pdSchedules
 	slackChannelID string
 	topicTemplate  *template.Template
+	format         string
+	schedule       string
 	dryRun         bool
 	pretendUsers   bool
 	slChannels     *channelList
+
+	// scheduleProviders records which OnCallProvider each schedule in
+	// pdSchedules was resolved against, keyed by the schedule's string
+	// representation, so runSlackSync dispatches the matching on-call
+	// lookup instead of always asking PagerDuty.
+	scheduleProviders map[string]string
+}
+
+// Equal reports whether two runSlackSync values describe the same desired
+// sync, along with a human-readable reason for the first divergence found.
+func (r runSlackSync) Equal(other runSlackSync) (bool, string) {
+	if r.name != other.name {
+		return false, fmt.Sprintf("name changed: %q -> %q", r.name, other.name)
+	}
+	if r.slackChannelID != other.slackChannelID {
+		return false, fmt.Sprintf("Slack channel changed: %q -> %q", r.slackChannelID, other.slackChannelID)
+	}
+	if r.dryRun != other.dryRun {
+		return false, fmt.Sprintf("dryRun changed: %t -> %t", r.dryRun, other.dryRun)
+	}
+	if r.pretendUsers != other.pretendUsers {
+		return false, fmt.Sprintf("pretendUsers changed: %t -> %t", r.pretendUsers, other.pretendUsers)
+	}
+	if r.schedule != other.schedule {
+		return false, fmt.Sprintf("schedule changed: %q -> %q", r.schedule, other.schedule)
+	}
+	if r.format != other.format {
+		return false, fmt.Sprintf("format changed: %q -> %q", r.format, other.format)
+	}
+
+	before := scheduleSignatures(r.pdSchedules)
+	after := scheduleSignatures(other.pdSchedules)
+	if before != after {
+		return false, fmt.Sprintf("schedules changed: %q -> %q", before, after)
+	}
+
+	beforeProviders := scheduleProviderSignature(r.scheduleProviders)
+	afterProviders := scheduleProviderSignature(other.scheduleProviders)
+	if beforeProviders != afterProviders {
+		return false, fmt.Sprintf("schedule providers changed: %q -> %q", beforeProviders, afterProviders)
+	}
+
+	return true, ""
+}
+
+// scheduleProviderSignature renders a schedule->provider map as a sorted,
+// comparable string, the same way scheduleSignatures does for pdSchedules,
+// so reassigning a schedule from one OnCallProvider to another is detected
+// as config drift.
+func scheduleProviderSignature(scheduleProviders map[string]string) string {
+	sigs := make([]string, 0, len(scheduleProviders))
+	for schedule, provider := range scheduleProviders {
+		sigs = append(sigs, fmt.Sprintf("%s=%s", schedule, provider))
+	}
+	sort.Strings(sigs)
+	return strings.Join(sigs, ",")
+}
+
+func scheduleSignatures(schedules pdSchedules) string {
+	sigs := make([]string, 0, len(schedules))
+	for _, schedule := range schedules {
+		sigs = append(sigs, fmt.Sprintf("%s", schedule))
+	}
+	sort.Strings(sigs)
+	return strings.Join(sigs, ",")
 }
 
 type syncerParams struct {
-	pdClient        *pagerDutyClient
-	slClient        *slackMetaClient
+	pdClient        pdAPI
+	goAlertClient   OnCallProvider
+	slClient        slackAPI
 	slackUsers      slackUsers
 	slackUserGroups UserGroups
 }
 
+// onCallProviders returns the OnCallProvider for each supported provider
+// name. PagerDuty is wrapped in the same OnCallProvider abstraction GoAlert
+// implements, rather than being special-cased inline, so getSchedule and
+// getOnCallUser dispatch both providers identically.
+func (sp syncerParams) onCallProviders() map[string]OnCallProvider {
+	providers := map[string]OnCallProvider{
+		"pagerduty": newPagerDutyProvider(sp.pdClient),
+	}
+	if sp.goAlertClient != nil {
+		providers["goalert"] = sp.goAlertClient
+	}
+	return providers
+}
+
+// getSchedule resolves a configured schedule against the requested
+// provider.
+func (sp syncerParams) getSchedule(ctx context.Context, provider string, schedule ConfigSchedule) (*pdSchedule, error) {
+	p, ok := sp.onCallProviders()[provider]
+	if !ok {
+		if provider == "goalert" {
+			return nil, fmt.Errorf("schedule %s requires provider=goalert but no GoAlert client is configured", schedule)
+		}
+		return nil, fmt.Errorf("unknown provider %q", provider)
+	}
+
+	ocSchedule, err := p.GetSchedule(ctx, schedule.ID, schedule.Name)
+	if err != nil || ocSchedule == nil {
+		return nil, err
+	}
+	return &pdSchedule{id: ocSchedule.ID, name: ocSchedule.Name}, nil
+}
+
+// getOnCallUser resolves the user currently on call for schedule against
+// the requested provider, mirroring getSchedule's provider dispatch so a
+// GoAlert-backed schedule is never looked up against the PagerDuty client.
+func (sp syncerParams) getOnCallUser(ctx context.Context, provider string, schedule pdSchedule) (*OnCallUser, error) {
+	p, ok := sp.onCallProviders()[provider]
+	if !ok {
+		if provider == "goalert" {
+			return nil, fmt.Errorf("schedule %s requires provider=goalert but no GoAlert client is configured", schedule)
+		}
+		return nil, fmt.Errorf("unknown provider %q", provider)
+	}
+	return p.GetOnCallUser(ctx, OnCallSchedule{ID: schedule.id, Name: schedule.name})
+}
+
 func (sp syncerParams) createSlackSyncs(ctx context.Context, cfg config) ([]runSlackSync, error) {
 	var slSyncs []runSlackSync
 
@@ -35,16 +151,26 @@ func (sp syncerParams) createSlackSyncs(ctx context.Context, cfg config) ([]runS
 			name:         cfgSlSync.Name,
 			pretendUsers: cfgSlSync.PretendUsers,
 			dryRun:       cfgSlSync.DryRun,
+			format:       cfgSlSync.format(),
+			schedule:     cfgSlSync.Schedule,
 		}
 
 		if cfgSlSync.Template == nil {
 			fmt.Printf("Slack sync %s: skipping topic handling because template is undefined\n", slSync.name)
 		}
 
+		provider := cfgSlSync.provider()
+
 		pdSchedules := pdSchedules{}
-		fmt.Printf("Slack sync %s: Getting PagerDuty schedules\n", slSync.name)
+		scheduleProviders := map[string]string{}
+		fmt.Printf("Slack sync %s: Getting %s schedules\n", slSync.name, provider)
 		for _, schedule := range cfgSlSync.Schedules {
-			pdSchedule, err := sp.pdClient.getSchedule(ctx, schedule.ID, schedule.Name)
+			scheduleProvider := schedule.Provider
+			if scheduleProvider == "" {
+				scheduleProvider = provider
+			}
+
+			pdSchedule, err := sp.getSchedule(ctx, scheduleProvider, schedule)
 			if err != nil {
 				return nil, fmt.Errorf("failed to create slack sync %q: failed to get schedule %s: %s", slSync.name, schedule, err)
 			}
@@ -52,6 +178,7 @@ func (sp syncerParams) createSlackSyncs(ctx context.Context, cfg config) ([]runS
 			if pdSchedule == nil {
 				return nil, fmt.Errorf("failed to create slack sync %q: schedule %s not found", slSync.name, schedule)
 			}
+			scheduleProviders[fmt.Sprintf("%s", *pdSchedule)] = scheduleProvider
 
 			for _, cfgUserGroup := range schedule.UserGroups {
 				ug := sp.slackUserGroups.find(cfgUserGroup)
@@ -72,7 +199,8 @@ func (sp syncerParams) createSlackSyncs(ctx context.Context, cfg config) ([]runS
 			}
 		}
 		slSync.pdSchedules = pdSchedules
-		fmt.Printf("Slack sync %s: found %d PagerDuty schedule(s)\n", slSync.name, len(pdSchedules))
+		slSync.scheduleProviders = scheduleProviders
+		fmt.Printf("Slack sync %s: found %d %s schedule(s)\n", slSync.name, len(pdSchedules), provider)
 
 		slSyncs = append(slSyncs, slSync)
 	}
@@ -82,14 +210,45 @@ func (sp syncerParams) createSlackSyncs(ctx context.Context, cfg config) ([]runS
 
 type syncer struct {
 	syncerParams
+	stateCache *stateCache
+
+	mu      sync.Mutex
+	lastRun map[string]runSlackSync
 }
 
 func newSyncer(sp syncerParams) *syncer {
 	return &syncer{
 		syncerParams: sp,
+		lastRun:      map[string]runSlackSync{},
 	}
 }
 
+// configChanged reports whether slackSync's configuration has drifted since
+// the last time it ran, even if the rendered desired state happens to
+// coincide (e.g. a schedule swap that still resolves to the same on-call
+// user). It records slackSync as the new baseline before returning.
+func (s *syncer) configChanged(slackSync runSlackSync) (bool, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	last, ok := s.lastRun[slackSync.name]
+	s.lastRun[slackSync.name] = slackSync
+	if !ok {
+		return true, "no prior run recorded"
+	}
+
+	equal, reason := last.Equal(slackSync)
+	return !equal, reason
+}
+
+// withStateCache enables the idempotency guard: runSlackSync will skip
+// Slack API writes for a sync whose desired state matches what was last
+// applied, as recorded in sc.
+func (s *syncer) withStateCache(sc *stateCache) *syncer {
+	s.stateCache = sc
+	return s
+}
+
 func (s *syncer) Run(ctx context.Context, slackSyncs []runSlackSync, failFast bool) error {
 	for _, slackSync := range slackSyncs {
 		err := s.runSlackSync(ctx, slackSync)
@@ -132,39 +291,76 @@ func (s *syncer) joinChannel(ctx context.Context, slackSync runSlackSync) error
 	return nil
 }
 
-func (s *syncer) updateTopic(ctx context.Context, slackSync runSlackSync, slackUserIDByScheduleName map[string]string) error {
+// renderTemplate executes the sync's template, if any, against the
+// structured per-schedule context, returning "" when there is no template
+// to render.
+func renderTemplate(slackSync runSlackSync, tmplCtx TemplateContext) (string, error) {
+	if slackSync.topicTemplate == nil {
+		return "", nil
+	}
+
+	var buf bytes.Buffer
+	fmt.Printf("Executing template for %s with %d schedule(s)\n", slackSync.name, len(tmplCtx.Schedules))
+	if err := slackSync.topicTemplate.Execute(&buf, tmplCtx); err != nil {
+		return "", fmt.Errorf("failed to render template: %s", err)
+	}
+
+	return buf.String(), nil
+}
+
+// isBlocksFormat reports whether rendered should be posted as a Block Kit
+// payload on a pinned message rather than as a plain-text channel topic:
+// either the sync is explicitly configured with format: blocks, or the
+// rendered output itself looks like a JSON object.
+func isBlocksFormat(format string, rendered string) bool {
+	if format == "blocks" {
+		return true
+	}
+	return strings.HasPrefix(strings.TrimSpace(rendered), "{")
+}
+
+// updateRendered applies the sync's rendered template output, either as a
+// channel topic or, for Block Kit payloads, as the sync's pinned card
+// message (posted and pinned on the first run, chat.update afterwards).
+// It returns the card message timestamp to persist for
+// the next run (unchanged for topic-mode syncs).
+func (s *syncer) updateRendered(ctx context.Context, slackSync runSlackSync, rendered string, cardMessageTS string) (string, error) {
 	if slackSync.dryRun {
-		return nil
+		return cardMessageTS, nil
 	}
 
 	if slackSync.topicTemplate == nil {
 		fmt.Println("Skipping topic update")
-		return nil
+		return cardMessageTS, nil
 	}
 
-	var buf bytes.Buffer
-	fmt.Printf("Executing template with Slack user IDs by schedule name: %s\n", slackUserIDByScheduleName)
-	err := slackSync.topicTemplate.Execute(&buf, slackUserIDByScheduleName)
-	if err != nil {
-		return fmt.Errorf("failed to render template: %s", err)
+	if isBlocksFormat(slackSync.format, rendered) {
+		newTS, err := s.slClient.updateCard(ctx, slackSync.slackChannelID, cardMessageTS, rendered, slackSync.dryRun)
+		if err != nil {
+			return cardMessageTS, fmt.Errorf("failed to update on-call card: %s", err)
+		}
+		return newTS, nil
 	}
 
-	topic := buf.String()
-	err = s.slClient.updateTopic(ctx, slackSync.slackChannelID, topic, slackSync.dryRun)
-	if err != nil {
-		return fmt.Errorf("failed to update topic: %s", err)
+	if err := s.slClient.updateTopic(ctx, slackSync.slackChannelID, rendered, slackSync.dryRun); err != nil {
+		return cardMessageTS, fmt.Errorf("failed to update topic: %s", err)
 	}
-	return nil
+	return cardMessageTS, nil
 }
 
 func (s *syncer) runSlackSync(ctx context.Context, slackSync runSlackSync) error {
 	s.joinChannel(ctx, slackSync)
 
 	ocgs := oncallGroups{}
-	slackUserIDByScheduleName := map[string]string{}
+	groupMembers := map[string][]string{}
+	var tmplSchedules []TemplateSchedule
 	for _, schedule := range slackSync.pdSchedules {
 		fmt.Printf("Processing schedule %s\n", schedule)
-		onCallUser, err := s.pdClient.getOnCallUser(ctx, schedule)
+		provider := slackSync.scheduleProviders[fmt.Sprintf("%s", schedule)]
+		if provider == "" {
+			provider = "pagerduty"
+		}
+		onCallUser, err := s.getOnCallUser(ctx, provider, schedule)
 		if err != nil {
 			return fmt.Errorf("failed to get on call user for schedule %q: %s", schedule.name, err)
 		}
@@ -177,6 +373,8 @@ func (s *syncer) runSlackSync(ctx context.Context, slackSync runSlackSync) error
 		for _, userGroup := range schedule.userGroups {
 			fmt.Printf("Ensuring member %s for user group %s\n", slUser.id, userGroup)
 			ocgs.getOrCreate(userGroup).ensureMember(slUser.id)
+			groupKey := fmt.Sprintf("%s", userGroup)
+			groupMembers[groupKey] = append(groupMembers[groupKey], slUser.id)
 		}
 
 		slUserID := slUser.id
@@ -185,16 +383,60 @@ func (s *syncer) runSlackSync(ctx context.Context, slackSync runSlackSync) error
 		}
 
 		cleanScheduleName := notAlphaNumRE.ReplaceAllString(schedule.name, "")
-		slackUserIDByScheduleName[cleanScheduleName] = slUserID
+		tmplSchedules = append(tmplSchedules, TemplateSchedule{
+			Name:            cleanScheduleName,
+			PDUser:          TemplateUser{Email: onCallUser.Email},
+			SlackUser:       TemplateSlackUser{ID: slUserID, Handle: slUser.handle},
+			EscalationLevel: onCallUser.EscalationLevel,
+			RotationEndsAt:  onCallUser.RotationEndsAt,
+		})
+	}
+
+	rendered, err := renderTemplate(slackSync, TemplateContext{Schedules: tmplSchedules})
+	if err != nil {
+		return fmt.Errorf("failed to channel template: %s", err)
+	}
+
+	cardMessageTS := ""
+	if s.stateCache != nil {
+		if last, ok := s.stateCache.get(slackSync.name); ok {
+			cardMessageTS = last.CardMessageTS
+		}
+	}
+
+	configChanged, configChangeReason := s.configChanged(slackSync)
+
+	desired := syncState{Topic: rendered, GroupMembers: groupMembers, CardMessageTS: cardMessageTS}
+	if s.stateCache != nil && !slackSync.dryRun {
+		if last, ok := s.stateCache.get(slackSync.name); ok {
+			if equal, _ := desired.Equal(last); equal && !configChanged {
+				fmt.Printf("Slack sync %s: desired state unchanged since last run, skipping Slack writes\n", slackSync.name)
+				return nil
+			} else if equal {
+				fmt.Printf("Slack sync %s: resyncing because %s\n", slackSync.name, configChangeReason)
+			} else {
+				_, reason := last.Equal(desired)
+				fmt.Printf("Slack sync %s: resyncing because %s\n", slackSync.name, reason)
+			}
+		}
 	}
 
 	if err := s.slClient.updateOncallGroupMembers(ctx, ocgs, slackSync.dryRun); err != nil {
 		return fmt.Errorf("failed to update on-call user group members: %s", err)
 	}
 
-	if err := s.updateTopic(ctx, slackSync, slackUserIDByScheduleName); err != nil {
+	newCardMessageTS, err := s.updateRendered(ctx, slackSync, rendered, cardMessageTS)
+	if err != nil {
 		return fmt.Errorf("failed to channel template: %s", err)
 	}
+	desired.CardMessageTS = newCardMessageTS
+
+	if s.stateCache != nil && !slackSync.dryRun {
+		s.stateCache.set(slackSync.name, desired)
+		if err := s.stateCache.save(); err != nil {
+			fmt.Fprintf(os.Stderr, "Slack sync %s: failed to persist state cache: %s\n", slackSync.name, err)
+		}
+	}
 
 	return nil
 }
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/halkeye/pdsync/internal/mockslack"
+)
+
+// fakePDClient is a pdAPI implementation that always resolves the same
+// schedule to the same on-call user, standing in for a real PagerDuty
+// client in the end-to-end test below.
+type fakePDClient struct {
+	schedule pdSchedule
+	onCall   OnCallUser
+}
+
+func (f *fakePDClient) getSchedule(_ context.Context, _, _ string) (*pdSchedule, error) {
+	return &f.schedule, nil
+}
+
+func (f *fakePDClient) getOnCallUser(_ context.Context, _ pdSchedule) (*OnCallUser, error) {
+	return &f.onCall, nil
+}
+
+// e2eSlackClient is a slackAPI implementation that talks to a real HTTP
+// server (internal/mockslack in the test below) at slackAPIBaseURL(),
+// exercising the SLACK_API_URL override end to end instead of calling the
+// real Slack Web API.
+type e2eSlackClient struct {
+	baseURL string
+	http    *http.Client
+}
+
+func newE2ESlackClient() *e2eSlackClient {
+	return &e2eSlackClient{baseURL: slackAPIBaseURL(), http: http.DefaultClient}
+}
+
+func (c *e2eSlackClient) post(ctx context.Context, method string, form url.Values) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+method, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var out map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	if ok, _ := out["ok"].(bool); !ok {
+		return out, fmt.Errorf("slack API error: %v", out["error"])
+	}
+	return out, nil
+}
+
+func (c *e2eSlackClient) joinChannel(ctx context.Context, channelID string) (bool, error) {
+	if _, err := c.post(ctx, "conversations.join", url.Values{"channel": {channelID}}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *e2eSlackClient) updateTopic(ctx context.Context, channelID, topic string, dryRun bool) error {
+	if dryRun {
+		return nil
+	}
+	_, err := c.post(ctx, "conversations.setTopic", url.Values{"channel": {channelID}, "topic": {topic}})
+	return err
+}
+
+func (c *e2eSlackClient) updateCard(_ context.Context, _, _, _ string, _ bool) (string, error) {
+	return "", errors.New("updateCard is not exercised by the end-to-end test harness")
+}
+
+func (c *e2eSlackClient) updateOncallGroupMembers(_ context.Context, groups oncallGroups, dryRun bool) error {
+	if dryRun || len(groups) == 0 {
+		return nil
+	}
+	return errors.New("updateOncallGroupMembers is not exercised by the end-to-end test harness")
+}
+
+func (c *e2eSlackClient) listChannels(_ context.Context) (channelList, error) {
+	return nil, errors.New("listChannels is not exercised by the end-to-end test harness")
+}
+
+func (c *e2eSlackClient) listUsers(_ context.Context) (slackUsers, error) {
+	return nil, errors.New("listUsers is not exercised by the end-to-end test harness")
+}
+
+func (c *e2eSlackClient) listUserGroups(_ context.Context) (UserGroups, error) {
+	return nil, errors.New("listUserGroups is not exercised by the end-to-end test harness")
+}
+
+// TestSyncerEndToEnd drives syncer.Run against a fake pagerDutyClient and a
+// mockslack-backed Slack client, asserting that the resulting Slack channel
+// topic reflects the on-call user the fake PagerDuty client reported.
+func TestSyncerEndToEnd(t *testing.T) {
+	mock := mockslack.New(
+		[]mockslack.Channel{{ID: "C1", Name: "oncall"}},
+		[]mockslack.User{{ID: "U1", Name: "alice", Email: "alice@example.com"}},
+		nil,
+	)
+	defer mock.Close()
+
+	t.Setenv("SLACK_API_URL", mock.URL+"/")
+
+	slUsers, err := manifestUsers(&slackManifest{
+		Users: []manifestEntry{{ID: "U1", Name: "alice", Handle: "alice", Email: "alice@example.com"}},
+	})
+	if err != nil {
+		t.Fatalf("manifestUsers: %s", err)
+	}
+
+	schedule := pdSchedule{id: "SCHED1", name: "Primary"}
+	onCall := OnCallUser{ID: "PDU1", Email: "alice@example.com"}
+
+	tmpl := template.Must(template.New("topic").Funcs(templateFuncMap).Parse(
+		"On call: {{with index .Schedules 0}}{{.SlackUser.Handle}}{{end}}",
+	))
+
+	slSync := runSlackSync{
+		name:              "primary",
+		pdSchedules:       pdSchedules{schedule},
+		slackChannelID:    "C1",
+		topicTemplate:     tmpl,
+		format:            "topic",
+		scheduleProviders: map[string]string{fmt.Sprintf("%s", schedule): "pagerduty"},
+	}
+
+	sp := syncerParams{
+		pdClient:   &fakePDClient{schedule: schedule, onCall: onCall},
+		slClient:   newE2ESlackClient(),
+		slackUsers: slUsers,
+	}
+	sc := &stateCache{byName: map[string]syncState{}}
+	s := newSyncer(sp).withStateCache(sc)
+
+	if err := s.Run(context.Background(), []runSlackSync{slSync}, true); err != nil {
+		t.Fatalf("Run failed: %s", err)
+	}
+
+	got := mock.Channel("C1")
+	if got == nil {
+		t.Fatal("channel C1 vanished from mock Slack server")
+	}
+	if want := "On call: alice"; got.Topic != want {
+		t.Errorf("channel topic = %q, want %q", got.Topic, want)
+	}
+
+	state, ok := sc.get("primary")
+	if !ok {
+		t.Fatalf("state cache has no entry for sync %q after Run", "primary")
+	}
+	if state.Topic != got.Topic {
+		t.Errorf("persisted state topic = %q, want %q", state.Topic, got.Topic)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TemplateUser is the PagerDuty side of a schedule's current on-call user,
+// as exposed to topic/card templates.
+type TemplateUser struct {
+	Email string
+}
+
+// TemplateSlackUser is the Slack side of a schedule's current on-call user,
+// as exposed to topic/card templates.
+type TemplateSlackUser struct {
+	ID     string
+	Handle string
+}
+
+// TemplateSchedule is the per-schedule context exposed to topic/card
+// templates as an entry of .Schedules.
+type TemplateSchedule struct {
+	Name            string
+	PDUser          TemplateUser
+	SlackUser       TemplateSlackUser
+	EscalationLevel int
+	RotationEndsAt  time.Time
+}
+
+// TemplateContext is the data handed to a Slack sync's template. It
+// replaces the old flat map[string]string of schedule name to Slack user
+// ID with structured per-schedule data.
+type TemplateContext struct {
+	Schedules []TemplateSchedule
+}
+
+// templateFuncMap is registered on every Slack sync template, in addition
+// to the built-in text/template functions.
+var templateFuncMap = template.FuncMap{
+	"mention":  func(slackUserID string) string { return "<@" + slackUserID + ">" },
+	"channel":  func(slackChannelID string) string { return "<#" + slackChannelID + ">" },
+	"date":     func(t time.Time) string { return t.Format("2006-01-02") },
+	"duration": func(d time.Duration) string { return d.Round(time.Minute).String() },
+	"emoji":    func(name string) string { return ":" + name + ":" },
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+}